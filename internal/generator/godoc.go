@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// godocRenderer renders a node tree as a Go 1.19+ doc comment: links use
+// the "[text]" reference syntax with the target collected into a trailing
+// "[text]: url" link definition block (see go/doc/comment), code spans use
+// backticks, and code blocks are indented by a tab so go/doc treats them
+// as preformatted.
+type godocRenderer struct {
+	links []godocLink
+}
+
+type godocLink struct {
+	text, href string
+}
+
+func (r *godocRenderer) Text(text string) string { return text }
+
+func (r *godocRenderer) Link(text, href string) string {
+	r.links = append(r.links, godocLink{text, href})
+	return "[" + text + "]"
+}
+
+func (r *godocRenderer) Image(alt string) string { return alt }
+
+func (r *godocRenderer) CodeSpan(code string) string { return "`" + code + "`" }
+
+func (r *godocRenderer) CodeBlock(code string) string {
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		lines[i] = "\t" + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *godocRenderer) Paragraph(text string) string { return text }
+
+func (r *godocRenderer) List(items []string, ordered bool) string {
+	var b strings.Builder
+	for i, item := range items {
+		if ordered {
+			fmt.Fprintf(&b, " %d. %s\n", i+1, item)
+		} else {
+			fmt.Fprintf(&b, " - %s\n", item)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (r *godocRenderer) Blockquote(text string) string { return text }
+
+// linkDefs renders the trailing "[text]: url" block for links collected
+// while rendering, or "" if none were seen.
+func (r *godocRenderer) linkDefs() string {
+	if len(r.links) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, link := range r.links {
+		fmt.Fprintf(&b, "[%s]: %s\n", link.text, link.href)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderGodoc renders a node tree as a Go doc comment body (without the
+// leading "// " prefix, which callers add per line).
+func renderGodoc(nodes []*HTMLNode) string {
+	r := &godocRenderer{}
+	body := renderDocument(nodes, r, "\n\n")
+	if defs := r.linkDefs(); defs != "" {
+		return body + "\n\n" + defs
+	}
+	return body
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// NodeKind identifies the shape of a parsed HTML node so serializers don't
+// have to re-inspect tag names.
+type NodeKind int
+
+const (
+	KindText NodeKind = iota
+	KindLink
+	KindImage
+	KindBlock
+	KindInline
+	KindCode
+	KindList
+	KindBlockquote
+)
+
+// HTMLNode is a small typed tree produced by parseHTML. It carries just
+// enough of the source markup (href, alt, list/code-block-ness) for the
+// serializers in render.go to rebuild whatever output shape they need,
+// without re-parsing or re-matching tags.
+type HTMLNode struct {
+	Kind     NodeKind
+	Text     string
+	Href     string
+	Alt      string
+	Ordered  bool // KindList: <ol> vs <ul>
+	IsBlock  bool // KindCode: <pre> vs <code>
+	Children []*HTMLNode
+}
+
+// parseHTML walks the doc-comment HTML Telegram ships in its Bot API
+// reference (p/div/li/blockquote/br, a, img, code/pre, and inline emphasis
+// tags) into an HTMLNode tree. Unlike the old regexp chain this handles
+// arbitrarily nested inline elements, attribute order, and HTML entities
+// correctly, since golang.org/x/net/html does the actual tokenizing.
+func parseHTML(text string) []*HTMLNode {
+	ctx := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	roots, err := html.ParseFragment(strings.NewReader(text), ctx)
+	exitOnErr(err)
+
+	nodes := make([]*HTMLNode, 0, len(roots))
+	for _, root := range roots {
+		nodes = append(nodes, convertHTMLNode(root))
+	}
+	return nodes
+}
+
+func convertHTMLNode(n *html.Node) *HTMLNode {
+	switch n.Type {
+	case html.TextNode:
+		return &HTMLNode{Kind: KindText, Text: n.Data}
+	case html.ElementNode:
+		switch n.Data {
+		case "a":
+			return &HTMLNode{Kind: KindLink, Href: htmlAttr(n, "href"), Children: convertHTMLChildren(n)}
+		case "img":
+			return &HTMLNode{Kind: KindImage, Alt: htmlAttr(n, "alt")}
+		case "code":
+			return &HTMLNode{Kind: KindCode, Children: convertHTMLChildren(n)}
+		case "pre":
+			return &HTMLNode{Kind: KindCode, IsBlock: true, Children: preChildren(n)}
+		case "ul":
+			return &HTMLNode{Kind: KindList, Children: convertHTMLChildren(n)}
+		case "ol":
+			return &HTMLNode{Kind: KindList, Ordered: true, Children: convertHTMLChildren(n)}
+		case "blockquote":
+			return &HTMLNode{Kind: KindBlockquote, Children: convertHTMLChildren(n)}
+		case "p", "div", "li":
+			return &HTMLNode{Kind: KindBlock, Children: convertHTMLChildren(n)}
+		case "br":
+			return &HTMLNode{Kind: KindBlock}
+		default:
+			return &HTMLNode{Kind: KindInline, Children: convertHTMLChildren(n)}
+		}
+	default:
+		return &HTMLNode{Kind: KindInline, Children: convertHTMLChildren(n)}
+	}
+}
+
+// preChildren unwraps the common <pre><code>...</code></pre> nesting so the
+// resulting KindCode node's Children are the actual text, not another
+// (ignored) KindCode wrapper.
+func preChildren(n *html.Node) []*HTMLNode {
+	if n.FirstChild != nil && n.FirstChild == n.LastChild && n.FirstChild.Type == html.ElementNode && n.FirstChild.Data == "code" {
+		return convertHTMLChildren(n.FirstChild)
+	}
+	return convertHTMLChildren(n)
+}
+
+func convertHTMLChildren(n *html.Node) []*HTMLNode {
+	var children []*HTMLNode
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, convertHTMLNode(c))
+	}
+	return children
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
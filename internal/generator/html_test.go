@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestParseHTMLNestedInlineLink(t *testing.T) {
+	nodes := parseHTML(`<a href="https://example.com"><code>foo</code></a>`)
+	if len(nodes) != 1 {
+		t.Fatalf("got %d nodes, want 1", len(nodes))
+	}
+
+	link := nodes[0]
+	if link.Kind != KindLink {
+		t.Fatalf("Kind = %v, want KindLink", link.Kind)
+	}
+	if link.Href != "https://example.com" {
+		t.Errorf("Href = %q", link.Href)
+	}
+	if len(link.Children) != 1 || link.Children[0].Kind != KindCode {
+		t.Fatalf("Children = %#v, want a single KindCode child", link.Children)
+	}
+
+	code := link.Children[0]
+	if len(code.Children) != 1 || code.Children[0].Kind != KindText || code.Children[0].Text != "foo" {
+		t.Errorf("code.Children = %#v, want a single \"foo\" text node", code.Children)
+	}
+}
+
+func TestParseHTMLAttributeEntity(t *testing.T) {
+	nodes := parseHTML(`<a href="/path?a=1&amp;b=2">link</a>`)
+	if len(nodes) != 1 || nodes[0].Kind != KindLink {
+		t.Fatalf("got %#v", nodes)
+	}
+	if nodes[0].Href != "/path?a=1&b=2" {
+		t.Errorf("Href = %q, want the &amp; entity unescaped", nodes[0].Href)
+	}
+}
+
+func TestParseHTMLImageAltEntity(t *testing.T) {
+	nodes := parseHTML(`<img src="x.png" alt="a &amp; b">`)
+	if len(nodes) != 1 || nodes[0].Kind != KindImage || nodes[0].Alt != "a & b" {
+		t.Fatalf("got %#v, want a single image node with Alt \"a & b\"", nodes)
+	}
+}
+
+func TestParseHTMLPreCodeUnwraps(t *testing.T) {
+	nodes := parseHTML("<pre><code>line1\nline2</code></pre>")
+	if len(nodes) != 1 || nodes[0].Kind != KindCode || !nodes[0].IsBlock {
+		t.Fatalf("got %#v, want a single block KindCode node", nodes)
+	}
+	if got := plainNodeText(nodes[0]); got != "line1\nline2" {
+		t.Errorf("plainNodeText = %q, want %q", got, "line1\nline2")
+	}
+}
+
+func TestParseHTMLOrderedList(t *testing.T) {
+	nodes := parseHTML("<ol><li>one</li><li>two</li></ol>")
+	if len(nodes) != 1 || nodes[0].Kind != KindList || !nodes[0].Ordered {
+		t.Fatalf("got %#v, want a single ordered KindList node", nodes)
+	}
+	if len(nodes[0].Children) != 2 {
+		t.Fatalf("got %d list items, want 2", len(nodes[0].Children))
+	}
+}
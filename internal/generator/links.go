@@ -0,0 +1,96 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// linkSentinelPattern matches the "--target--" sentinel renderSentinel
+// leaves around a link's href, so it can be rewritten into a real URL once
+// the surrounding markup has already been flattened to plain text.
+const linkSentinelPattern = `--(?P<target>[^-\s].*?)--`
+
+var linkSentinelRegexp = regexp.MustCompile(linkSentinelPattern)
+
+// LinkRule resolves one category of link target (an absolute URL, a path
+// relative to some docs root, an in-page anchor, ...) into its final URL.
+type LinkRule struct {
+	Match   func(target string) bool
+	Rewrite func(target string) string
+}
+
+// LinkResolver rewrites the "--target--" sentinels left by renderSentinel
+// into real URLs, trying each registered LinkRule in order and leaving the
+// target untouched if none match. It replaces the old baseURL/docsURL
+// globals: replaceHTML takes the resolver as a parameter instead of
+// reading package state, so a caller can build one resolver per docs
+// section (core docs, payments docs, mini-apps docs, ...).
+type LinkResolver struct {
+	rules []LinkRule
+}
+
+func NewLinkResolver(rules ...LinkRule) *LinkResolver {
+	return &LinkResolver{rules: rules}
+}
+
+// resolve tries each registered rule against target in order, returning
+// the rewritten URL and whether any rule actually matched.
+func (r *LinkResolver) resolve(target string) (string, bool) {
+	for _, rule := range r.rules {
+		if rule.Match(target) {
+			return rule.Rewrite(target), true
+		}
+	}
+	return target, false
+}
+
+func (r *LinkResolver) Resolve(target string) string {
+	resolved, _ := r.resolve(target)
+	return resolved
+}
+
+// rewriteSentinels turns "--target--" sentinels into "(url)" for targets a
+// registered LinkRule actually claims. The sentinel pattern alone can't
+// tell a link target from literal double-dashed prose ("--like this--"),
+// so a target no rule matches is left exactly as rendered instead of
+// getting wrapped in parens.
+func (r *LinkResolver) rewriteSentinels(text string) string {
+	return linkSentinelRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		target := linkSentinelRegexp.FindStringSubmatch(match)[linkSentinelRegexp.SubexpIndex("target")]
+		resolved, ok := r.resolve(target)
+		if !ok {
+			return match
+		}
+		return "(" + resolved + ")"
+	})
+}
+
+// ExternalLinkRule matches absolute http(s) URLs and passes them through
+// unchanged.
+func ExternalLinkRule() LinkRule {
+	return LinkRule{
+		Match: func(target string) bool {
+			return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+		},
+		Rewrite: func(target string) string { return target },
+	}
+}
+
+// InternalLinkRule resolves a "/path" target against base, e.g. the core
+// Bot API docs root or a bot-payments/mini-apps section root.
+func InternalLinkRule(base string) LinkRule {
+	return LinkRule{
+		Match:   func(target string) bool { return strings.HasPrefix(target, "/") },
+		Rewrite: func(target string) string { return base + target },
+	}
+}
+
+// AnchorLinkRule resolves a "#foo" target against page, the docs page it
+// was scraped from, so the same anchor isn't rewritten the same way
+// regardless of which page it actually came from.
+func AnchorLinkRule(page string) LinkRule {
+	return LinkRule{
+		Match:   func(target string) bool { return strings.HasPrefix(target, "#") },
+		Rewrite: func(target string) string { return page + target },
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestRewriteSentinelsLeavesUnmatchedTargetUnchanged(t *testing.T) {
+	r := NewLinkResolver(ExternalLinkRule())
+	text := "see --https://example.com-- and --foo-- too"
+	want := "see (https://example.com) and --foo-- too"
+	if got := r.rewriteSentinels(text); got != want {
+		t.Errorf("rewriteSentinels(%q) = %q, want %q", text, got, want)
+	}
+}
+
+func TestRewriteSentinelsMatchesRegisteredRule(t *testing.T) {
+	r := NewLinkResolver(InternalLinkRule("https://core.telegram.org/bots/api"))
+	text := "see --/faq--"
+	want := "see (https://core.telegram.org/bots/api/faq)"
+	if got := r.rewriteSentinels(text); got != want {
+		t.Errorf("rewriteSentinels(%q) = %q, want %q", text, got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownRenderer renders a CommonMark document: "[text](url)" links,
+// backtick code spans, fenced code blocks, and "-"/"1." list markers.
+type markdownRenderer struct{}
+
+func (markdownRenderer) Text(text string) string { return text }
+
+func (markdownRenderer) Link(text, href string) string {
+	return fmt.Sprintf("[%s](%s)", text, href)
+}
+
+func (markdownRenderer) Image(alt string) string { return alt }
+
+func (markdownRenderer) CodeSpan(code string) string { return "`" + code + "`" }
+
+func (markdownRenderer) CodeBlock(code string) string {
+	return "```\n" + code + "\n```"
+}
+
+func (markdownRenderer) Paragraph(text string) string { return text }
+
+func (markdownRenderer) List(items []string, ordered bool) string {
+	var b strings.Builder
+	for i, item := range items {
+		if ordered {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, item)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (markdownRenderer) Blockquote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderMarkdown renders a node tree as CommonMark Markdown.
+func renderMarkdown(nodes []*HTMLNode) string {
+	return renderDocument(nodes, markdownRenderer{}, "\n\n")
+}
@@ -0,0 +1,138 @@
+package main
+
+import "strings"
+
+// Renderer turns an HTMLNode tree into a concrete output format. Each
+// method renders one construct in isolation; renderDocument/renderInline
+// do the tree walking and call back into the Renderer so a new output
+// format only has to implement this interface, not another tree walker.
+type Renderer interface {
+	Text(text string) string
+	Link(text, href string) string
+	Image(alt string) string
+	CodeSpan(code string) string
+	CodeBlock(code string) string
+	Paragraph(text string) string
+	List(items []string, ordered bool) string
+	Blockquote(text string) string
+}
+
+// renderDocument renders a full node tree as a sequence of block elements
+// (paragraphs, lists, blockquotes, code blocks) joined by sep. Structured
+// renderers (Markdown, godoc) want a blank line between blocks so two
+// paragraphs don't merge into one; the plain sentinel renderer keeps the
+// single-newline legacy behavior.
+func renderDocument(nodes []*HTMLNode, r Renderer, sep string) string {
+	var blocks []string
+	for _, n := range nodes {
+		if b := renderBlock(n, r); b != "" {
+			blocks = append(blocks, b)
+		}
+	}
+	return strings.Join(blocks, sep)
+}
+
+func renderBlock(n *HTMLNode, r Renderer) string {
+	switch n.Kind {
+	case KindList:
+		items := make([]string, 0, len(n.Children))
+		for _, item := range n.Children {
+			items = append(items, renderInlineChildren(item.Children, r))
+		}
+		return r.List(items, n.Ordered)
+	case KindBlockquote:
+		return r.Blockquote(renderInlineChildren(n.Children, r))
+	case KindCode:
+		if n.IsBlock {
+			return r.CodeBlock(plainNodeText(n))
+		}
+		return r.Paragraph(r.CodeSpan(plainNodeText(n)))
+	case KindBlock:
+		text := renderInlineChildren(n.Children, r)
+		if text == "" {
+			return ""
+		}
+		return r.Paragraph(text)
+	default:
+		return renderInlineChildren([]*HTMLNode{n}, r)
+	}
+}
+
+func renderInlineChildren(nodes []*HTMLNode, r Renderer) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(renderInline(n, r))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func renderInline(n *HTMLNode, r Renderer) string {
+	switch n.Kind {
+	case KindText:
+		return r.Text(n.Text)
+	case KindImage:
+		return r.Image(n.Alt)
+	case KindLink:
+		return r.Link(renderInlineChildren(n.Children, r), n.Href)
+	case KindCode:
+		return r.CodeSpan(plainNodeText(n))
+	case KindBlock, KindInline:
+		return renderInlineChildren(n.Children, r)
+	case KindList, KindBlockquote:
+		return renderBlock(n, r)
+	default:
+		return ""
+	}
+}
+
+// plainNodeText flattens an HTMLNode tree to bare text: a link keeps its
+// text but not its href, and images are dropped entirely. Used by
+// removeHTML, and by block-level renderers that need the raw text inside a
+// <code>/<pre>.
+func plainNodeText(n *HTMLNode) string {
+	switch n.Kind {
+	case KindText:
+		return n.Text
+	case KindImage:
+		return ""
+	default: // KindLink, KindBlock, KindCode, KindInline, KindList, KindBlockquote
+		return joinNodeText(n.Children, plainNodeText)
+	}
+}
+
+func joinNodeText(nodes []*HTMLNode, render func(*HTMLNode) string) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(render(n))
+	}
+	return b.String()
+}
+
+// plainRenderer reproduces the plain-text shape replaceHTML has always
+// produced: link targets kept inline as a "--url--" sentinel, which the
+// URL-rewriting pass further down turns into "(url)".
+type plainRenderer struct{}
+
+func (plainRenderer) Text(text string) string { return text }
+
+func (plainRenderer) Link(text, href string) string {
+	return text + " --" + href + "--"
+}
+
+func (plainRenderer) Image(alt string) string { return alt }
+
+func (plainRenderer) CodeSpan(code string) string { return code }
+
+func (plainRenderer) CodeBlock(code string) string { return code }
+
+func (plainRenderer) Paragraph(text string) string { return text }
+
+func (plainRenderer) List(items []string, _ bool) string {
+	return strings.Join(items, "\n")
+}
+
+func (plainRenderer) Blockquote(text string) string { return text }
+
+func renderSentinel(nodes []*HTMLNode) string {
+	return renderDocument(nodes, plainRenderer{}, "\n")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSentinelNestedList(t *testing.T) {
+	nodes := parseHTML("<ul><li>item1<ul><li>nested1</li></ul></li></ul>")
+	got := renderSentinel(nodes)
+
+	if !strings.Contains(got, "item1") || !strings.Contains(got, "nested1") {
+		t.Fatalf("renderSentinel(%#v) = %q, want it to contain both \"item1\" and \"nested1\"", nodes, got)
+	}
+}
+
+func TestRenderSentinelNestedBlockquote(t *testing.T) {
+	nodes := parseHTML("<blockquote>outer<blockquote>inner</blockquote></blockquote>")
+	got := renderSentinel(nodes)
+
+	if !strings.Contains(got, "outer") || !strings.Contains(got, "inner") {
+		t.Fatalf("renderSentinel(%#v) = %q, want it to contain both \"outer\" and \"inner\"", nodes, got)
+	}
+}
+
+func TestRenderSentinelLink(t *testing.T) {
+	nodes := parseHTML(`<a href="https://example.com">text</a>`)
+	want := "text --https://example.com--"
+	if got := renderSentinel(nodes); got != want {
+		t.Errorf("renderSentinel(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownLinkAndCodeSpan(t *testing.T) {
+	nodes := parseHTML(`<p>see <a href="https://example.com">here</a> and <code>foo</code></p>`)
+	got := renderMarkdown(nodes)
+	want := "see [here](https://example.com) and `foo`"
+	if got != want {
+		t.Errorf("renderMarkdown(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownList(t *testing.T) {
+	nodes := parseHTML("<ol><li>one</li><li>two</li></ol>")
+	got := renderMarkdown(nodes)
+	want := "1. one\n2. two"
+	if got != want {
+		t.Errorf("renderMarkdown(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownMultipleParagraphs(t *testing.T) {
+	nodes := parseHTML("<p>first</p><p>second</p>")
+	got := renderMarkdown(nodes)
+	want := "first\n\nsecond"
+	if got != want {
+		t.Errorf("renderMarkdown(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRenderGodocMultipleParagraphs(t *testing.T) {
+	nodes := parseHTML("<p>first</p><p>second</p>")
+	got := renderGodoc(nodes)
+	want := "first\n\nsecond"
+	if got != want {
+		t.Errorf("renderGodoc(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRenderGodocLinkIsReferencedAndDefined(t *testing.T) {
+	nodes := parseHTML(`<p>see <a href="https://example.com">here</a></p>`)
+	got := renderGodoc(nodes)
+
+	if !strings.Contains(got, "[here]") {
+		t.Errorf("renderGodoc(...) = %q, want it to reference [here]", got)
+	}
+	if !strings.Contains(got, "[here]: https://example.com") {
+		t.Errorf("renderGodoc(...) = %q, want a trailing [here]: https://example.com link def", got)
+	}
+}
@@ -0,0 +1,241 @@
+// Package types resolves the type text the Bot API docs use for a field
+// ("Integer", "Array of PhotoSize", "InlineQueryResult", ...) into a typed
+// Ref describing the Go shape it should generate, replacing the old
+// ad-hoc switch-plus-string-prefix recursion in the generator.
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Ref describes a resolved Bot API type: what Go type it renders as,
+// whether it's optional (so callers know to pointer-wrap or omitempty),
+// and, for container types, what it wraps.
+type Ref interface {
+	Go() string
+	IsOptional() bool
+	Elem() Ref
+}
+
+// Primitive is a plain Go builtin: string, int, float64, bool.
+type Primitive struct {
+	Name     string
+	Optional bool
+}
+
+func (p Primitive) Go() string {
+	if p.Optional {
+		return "*" + p.Name
+	}
+	return p.Name
+}
+func (p Primitive) IsOptional() bool { return p.Optional }
+func (p Primitive) Elem() Ref        { return nil }
+
+// Named is a reference to another generated struct, e.g. "PhotoSize".
+type Named struct {
+	Name     string
+	Optional bool
+}
+
+func (n Named) Go() string {
+	if n.Optional {
+		return "*" + n.Name
+	}
+	return n.Name
+}
+func (n Named) IsOptional() bool { return n.Optional }
+func (n Named) Elem() Ref        { return nil }
+
+// Array is a "Array of X" reference. Optional arrays aren't pointer-wrapped
+// in this generator (a nil slice already serializes as omitted/null).
+type Array struct {
+	Of       Ref
+	Optional bool
+}
+
+func (a Array) Go() string       { return "[]" + a.Of.Go() }
+func (a Array) IsOptional() bool { return a.Optional }
+func (a Array) Elem() Ref        { return a.Of }
+
+// ChatID is Telegram's "Integer or String" chat identifier.
+type ChatID struct {
+	Optional bool
+}
+
+func (c ChatID) Go() string       { return "ChatID" }
+func (c ChatID) IsOptional() bool { return c.Optional }
+func (c ChatID) Elem() Ref        { return nil }
+
+// InputFileOrString is Telegram's "InputFile or String" media parameter.
+type InputFileOrString struct {
+	Optional bool
+}
+
+func (f InputFileOrString) Go() string {
+	if f.Optional {
+		return "*InputFile"
+	}
+	return "InputFile"
+}
+func (f InputFileOrString) IsOptional() bool { return f.Optional }
+func (f InputFileOrString) Elem() Ref        { return nil }
+
+// Union is one of Telegram's "One of the following" polymorphic types
+// (InlineQueryResult, MessageOrigin, ChatMember, ...). Go() still renders
+// as the bare interface name (a union field is declared no differently
+// from a Named one), but Union carries what Named can't: the JSON
+// Discriminator field and the Members it dispatches on, so a codegen pass
+// can emit the tagged interface and its unmarshal dispatch instead of
+// leaving the type as an undecodable plain reference.
+type Union struct {
+	Name          string
+	Discriminator string
+	Members       map[string]string
+	Optional      bool
+}
+
+func (u Union) Go() string       { return u.Name }
+func (u Union) IsOptional() bool { return u.Optional }
+func (u Union) Elem() Ref        { return nil }
+
+// GenerateInterface renders the tagged-interface declaration for this
+// union: an unexported marker method every member type implements.
+func (u Union) GenerateInterface() string {
+	return fmt.Sprintf("type %s interface {\n\tis%s()\n}", u.Name, u.Name)
+}
+
+// GenerateUnmarshal renders a standalone function that decodes raw JSON
+// into the concrete member type named by this union's discriminator
+// field, dispatching on it. The parameter/switch variable is always
+// named "discriminator", not u.Discriminator: several of the Bot API's
+// own discriminator field names ("type") are Go keywords, so the real
+// field name is only safe to use inside the error string, not as an
+// identifier.
+func (u Union) GenerateUnmarshal() string {
+	values := make([]string, 0, len(u.Members))
+	for value := range u.Members {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "func unmarshal%s(discriminator string, data []byte) (%s, error) {\n", u.Name, u.Name)
+	b.WriteString("\tswitch discriminator {\n")
+	for _, value := range values {
+		member := u.Members[value]
+		fmt.Fprintf(&b, "\tcase %q:\n\t\tvar v %s\n\t\terr := json.Unmarshal(data, &v)\n\t\treturn v, err\n", value, member)
+	}
+	fmt.Fprintf(&b, "\tdefault:\n\t\treturn nil, fmt.Errorf(\"%s: unknown %s %%q\", discriminator)\n\t}\n}", u.Name, u.Discriminator)
+	return b.String()
+}
+
+// knownUnions lists the Bot API's polymorphic types by the name the docs
+// give the field, along with the discriminator field and the concrete
+// member types documented under that field's "one of the following"
+// heading. The member list itself lives in surrounding prose the type
+// text doesn't carry, so it's hand-maintained here rather than parsed.
+var knownUnions = map[string]Union{
+	"InlineQueryResult": {
+		Name:          "InlineQueryResult",
+		Discriminator: "type",
+		Members: map[string]string{
+			"article":   "InlineQueryResultArticle",
+			"photo":     "InlineQueryResultPhoto",
+			"gif":       "InlineQueryResultGif",
+			"mpeg4_gif": "InlineQueryResultMpeg4Gif",
+			"video":     "InlineQueryResultVideo",
+			"audio":     "InlineQueryResultAudio",
+			"voice":     "InlineQueryResultVoice",
+			"document":  "InlineQueryResultDocument",
+			"location":  "InlineQueryResultLocation",
+			"venue":     "InlineQueryResultVenue",
+			"contact":   "InlineQueryResultContact",
+			"game":      "InlineQueryResultGame",
+			"sticker":   "InlineQueryResultCachedSticker",
+		},
+	},
+	"MessageOrigin": {
+		Name:          "MessageOrigin",
+		Discriminator: "type",
+		Members: map[string]string{
+			"user":        "MessageOriginUser",
+			"hidden_user": "MessageOriginHiddenUser",
+			"chat":        "MessageOriginChat",
+			"channel":     "MessageOriginChannel",
+		},
+	},
+	"ChatMember": {
+		Name:          "ChatMember",
+		Discriminator: "status",
+		Members: map[string]string{
+			"creator":       "ChatMemberOwner",
+			"administrator": "ChatMemberAdministrator",
+			"member":        "ChatMemberMember",
+			"restricted":    "ChatMemberRestricted",
+			"left":          "ChatMemberLeft",
+			"kicked":        "ChatMemberBanned",
+		},
+	},
+	"ReactionType": {
+		Name:          "ReactionType",
+		Discriminator: "type",
+		Members: map[string]string{
+			"emoji":        "ReactionTypeEmoji",
+			"custom_emoji": "ReactionTypeCustomEmoji",
+			"paid":         "ReactionTypePaid",
+		},
+	},
+}
+
+// GenerateUnionFiles renders the interface declaration and dispatching
+// unmarshal function for every known union, keyed by union name. Callers
+// that walk parsed fields and hit a Union Ref use this to emit the real
+// type instead of treating the union like a plain Named reference.
+func GenerateUnionFiles() map[string]string {
+	files := make(map[string]string, len(knownUnions))
+	for name, u := range knownUnions {
+		files[name] = u.GenerateInterface() + "\n\n" + u.GenerateUnmarshal() + "\n"
+	}
+	return files
+}
+
+// Parse resolves Bot API doc type text, e.g. "Integer", "Array of
+// PhotoSize", or "Array of Array of PhotoSize", into a Ref. It mirrors the
+// old parseType but returns a typed Ref instead of bare Go source, and
+// cuts the "Array of " prefix with strings.Cut instead of a TrimPrefix
+// chain so a second "Array of " (nested arrays) isn't silently dropped.
+func Parse(text string, optional bool) Ref {
+	text = strings.TrimSpace(text)
+
+	switch text {
+	case "String":
+		return Primitive{Name: "string", Optional: optional}
+	case "Integer", "Int":
+		return Primitive{Name: "int", Optional: optional}
+	case "Float number", "Float", "Integer or Float":
+		return Primitive{Name: "float64", Optional: optional}
+	case "Boolean", "True":
+		return Primitive{Name: "bool", Optional: optional}
+	case "Integer or String":
+		return ChatID{Optional: optional}
+	case "InputFile or String":
+		return InputFileOrString{Optional: optional}
+	}
+
+	if before, after, ok := strings.Cut(text, "Array of "); ok && before == "" {
+		return Array{Of: Parse(after, false), Optional: optional}
+	}
+	if before, after, ok := strings.Cut(text, "array of "); ok && before == "" {
+		return Array{Of: Parse(after, false), Optional: optional}
+	}
+
+	if spec, ok := knownUnions[text]; ok {
+		spec.Optional = optional
+		return spec
+	}
+
+	return Named{Name: text, Optional: optional}
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestParsePrimitives(t *testing.T) {
+	cases := []struct {
+		text     string
+		optional bool
+		want     string
+	}{
+		{"String", false, "string"},
+		{"String", true, "*string"},
+		{"Integer", false, "int"},
+		{"Int", false, "int"},
+		{"Float number", false, "float64"},
+		{"Float", true, "*float64"},
+		{"Integer or Float", false, "float64"},
+		{"Boolean", false, "bool"},
+		{"True", true, "*bool"},
+	}
+
+	for _, c := range cases {
+		got := Parse(c.text, c.optional).Go()
+		if got != c.want {
+			t.Errorf("Parse(%q, %v).Go() = %q, want %q", c.text, c.optional, got, c.want)
+		}
+	}
+}
+
+func TestParseChatIDAndInputFile(t *testing.T) {
+	if got := Parse("Integer or String", false); got.Go() != "ChatID" {
+		t.Errorf(`Parse("Integer or String", false).Go() = %q, want "ChatID"`, got.Go())
+	}
+
+	if got := Parse("InputFile or String", false).Go(); got != "InputFile" {
+		t.Errorf(`Parse("InputFile or String", false).Go() = %q, want "InputFile"`, got)
+	}
+	if got := Parse("InputFile or String", true).Go(); got != "*InputFile" {
+		t.Errorf(`Parse("InputFile or String", true).Go() = %q, want "*InputFile"`, got)
+	}
+}
+
+func TestParseArray(t *testing.T) {
+	ref := Parse("Array of PhotoSize", false)
+	if ref.Go() != "[]PhotoSize" {
+		t.Fatalf(`Go() = %q, want "[]PhotoSize"`, ref.Go())
+	}
+	elem, ok := ref.Elem().(Named)
+	if !ok || elem.Name != "PhotoSize" {
+		t.Fatalf("Elem() = %#v, want Named{Name: \"PhotoSize\"}", ref.Elem())
+	}
+}
+
+func TestParseNestedArray(t *testing.T) {
+	ref := Parse("Array of Array of PhotoSize", false)
+	if ref.Go() != "[][]PhotoSize" {
+		t.Fatalf(`Go() = %q, want "[][]PhotoSize"`, ref.Go())
+	}
+
+	inner, ok := ref.Elem().(Array)
+	if !ok {
+		t.Fatalf("Elem() = %#v, want Array", ref.Elem())
+	}
+	if inner.Go() != "[]PhotoSize" {
+		t.Fatalf(`Elem().Go() = %q, want "[]PhotoSize"`, inner.Go())
+	}
+}
+
+func TestParseLowercaseArrayPrefix(t *testing.T) {
+	if got := Parse("array of String", false).Go(); got != "[]string" {
+		t.Errorf(`Parse("array of String", false).Go() = %q, want "[]string"`, got)
+	}
+}
+
+func TestParseKnownUnion(t *testing.T) {
+	ref := Parse("InlineQueryResult", true)
+	u, ok := ref.(Union)
+	if !ok {
+		t.Fatalf("Parse(%q) = %#v, want Union", "InlineQueryResult", ref)
+	}
+	if !u.IsOptional() {
+		t.Errorf("Union.IsOptional() = false, want true")
+	}
+	if u.Go() != "InlineQueryResult" {
+		t.Errorf("Union.Go() = %q, want %q", u.Go(), "InlineQueryResult")
+	}
+}
+
+func TestUnionGenerateInterfaceAndUnmarshal(t *testing.T) {
+	ref := Parse("ChatMember", false)
+	u, ok := ref.(Union)
+	if !ok {
+		t.Fatalf("Parse(%q) = %#v, want Union", "ChatMember", ref)
+	}
+
+	iface := u.GenerateInterface()
+	if want := "type ChatMember interface {\n\tisChatMember()\n}"; iface != want {
+		t.Errorf("GenerateInterface() = %q, want %q", iface, want)
+	}
+
+	unmarshal := u.GenerateUnmarshal()
+	if want := `case "creator":`; !strings.Contains(unmarshal, want) {
+		t.Errorf("GenerateUnmarshal() = %q, want it to contain %q", unmarshal, want)
+	}
+	if want := "func unmarshalChatMember(discriminator string, data []byte) (ChatMember, error)"; !strings.Contains(unmarshal, want) {
+		t.Errorf("GenerateUnmarshal() = %q, want it to contain %q", unmarshal, want)
+	}
+}
+
+// TestGenerateUnmarshalParsesForKeywordDiscriminator guards against using
+// the real discriminator field name as a Go identifier: "type" (the
+// discriminator for InlineQueryResult, MessageOrigin and ReactionType) is
+// a reserved keyword, so a naive unmarshal%s(type string, ...) would be a
+// syntax error.
+func TestGenerateUnmarshalParsesForKeywordDiscriminator(t *testing.T) {
+	for _, name := range []string{"InlineQueryResult", "MessageOrigin", "ChatMember", "ReactionType"} {
+		u := knownUnions[name]
+		src := "package p\n\n" + u.GenerateInterface() + "\n\n" + u.GenerateUnmarshal() + "\n"
+
+		fset := token.NewFileSet()
+		if _, err := parser.ParseFile(fset, name+".go", src, 0); err != nil {
+			t.Errorf("generated source for %q is not valid Go: %v\n%s", name, err, src)
+		}
+	}
+}
+
+func TestGenerateUnionFiles(t *testing.T) {
+	files := GenerateUnionFiles()
+	for _, name := range []string{"InlineQueryResult", "MessageOrigin", "ChatMember", "ReactionType"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("GenerateUnionFiles()[%q] missing", name)
+		}
+	}
+}
+
+func TestParseNamedFallback(t *testing.T) {
+	ref := Parse("Message", false)
+	if _, ok := ref.(Named); !ok {
+		t.Fatalf("Parse(%q) = %#v, want Named", "Message", ref)
+	}
+	if ref.Elem() != nil {
+		t.Errorf("Named.Elem() = %#v, want nil", ref.Elem())
+	}
+}
@@ -2,45 +2,16 @@ package main
 
 import (
 	"fmt"
-	"html"
 	"os"
 	"regexp"
 	"strings"
-)
-
-const (
-	linkPattern = `<a.+?href="(.+?)".*?>(.+?)<\/a>`
-
-	externalURLPattern = `--(http[s]:\/\/.+?)--`
-	internalURLPattern = `--(\/.+?)--`
-	anchorURLPattern   = `--(#.+?)--`
-
-	imagePattern = `<img.+?alt="(.+?)".*?>`
-
-	tagNlPattern = `<(?:p|div|li|blockquote|br).*?>`
-	tagPattern   = `<.+?>`
 
-	tagElemPattern = `<.+?>(.+?)<\/.+?>`
-
-	multiSpacePattern = `(\s)\s+`
+	"github.com/wthsjy/telego/internal/generator/types"
 )
 
-var (
-	linkRegexp = regexp.MustCompile(linkPattern)
-
-	externalURLRegexp = regexp.MustCompile(externalURLPattern)
-	internalURLRegexp = regexp.MustCompile(internalURLPattern)
-	anchorURLRegexp   = regexp.MustCompile(anchorURLPattern)
-
-	imageRegexp = regexp.MustCompile(imagePattern)
-
-	tagRegexp   = regexp.MustCompile(tagPattern)
-	tagNlRegexp = regexp.MustCompile(tagNlPattern)
+const multiSpacePattern = `(\s)\s+`
 
-	tagElemRegexp = regexp.MustCompile(tagElemPattern)
-
-	multiSpaceRegexp = regexp.MustCompile(multiSpacePattern)
-)
+var multiSpaceRegexp = regexp.MustCompile(multiSpacePattern)
 
 func logInfof(format string, args ...any) {
 	fmt.Printf("[INFO] "+format+"\n", args...)
@@ -77,73 +48,24 @@ func removeNl(text string) string {
 	return strings.ReplaceAll(text, "\n", "")
 }
 
-func replaceHTML(text string) string {
-	text = imageRegexp.ReplaceAllString(text, "$1")
-
-	text = linkRegexp.ReplaceAllString(text, "$2 --$1--")
-
-	text = externalURLRegexp.ReplaceAllString(text, "($1)")
-	text = internalURLRegexp.ReplaceAllString(text, fmt.Sprintf("(%s$1)", baseURL))
-	text = anchorURLRegexp.ReplaceAllString(text, fmt.Sprintf("(%s$1)", docsURL))
-
-	text = tagNlRegexp.ReplaceAllString(text, "\n")
-	text = tagRegexp.ReplaceAllString(text, "")
-
-	text = html.UnescapeString(text)
+func replaceHTML(text string, resolver *LinkResolver) string {
+	text = renderSentinel(parseHTML(text))
+	text = resolver.rewriteSentinels(text)
 	text = trimSpaces(text)
 
 	return text
 }
 
 func removeHTML(text string) string {
-	text = tagElemRegexp.ReplaceAllString(text, "$1")
-	text = tagRegexp.ReplaceAllString(text, "")
-
-	text = html.UnescapeString(text)
-
-	return text
-}
-
-func splitTextToFitLine(text string) []string {
-	words := strings.Split(text, " ")
-	result := make([]string, 0)
-	line := strings.Builder{}
-	for _, word := range words {
-		if strings.Contains(word, "\n") {
-			ws := strings.Split(word, "\n")
-			if len(ws) != 2 {
-				os.Exit(2)
-			}
-
-			if line.Len()+len(ws[0])+1 > maxLineLen {
-				result = append(result, line.String())
-				line.Reset()
-			}
-
-			line.WriteString(ws[0] + " ")
-			result = append(result, line.String())
-			line.Reset()
-
-			word = ws[1]
-		}
-
-		if line.Len()+len(word)+1 > maxLineLen {
-			result = append(result, line.String())
-			line.Reset()
-		}
-		line.WriteString(word + " ")
-	}
-
-	if line.Len() != 0 {
-		result = append(result, line.String())
-	}
-
-	return result
+	return joinNodeText(parseHTML(text), plainNodeText)
 }
 
 func fitTextToLine(text, delimiter string) string {
-	lines := splitTextToFitLine(delimiter + text)
-	return strings.Join(lines, "\n"+delimiter)
+	opts := WrapOptions{
+		MaxWidth:     maxLineLen - graphemeWidth(delimiter),
+		PreserveURLs: true,
+	}
+	return splitTextToFitLine(text, opts).Join(delimiter)
 }
 
 func trimSpaces(text string) string {
@@ -180,36 +102,30 @@ func snakeToCamelCase(text string) string {
 	return result.String()
 }
 
-func parseType(text string, optional bool) string {
-	text = removeHTML(text)
-
-	switch text {
-	case "String":
-		return "string"
-	case "Integer", "Int":
-		return "int"
-	case "Float number", "Float":
-		return "float64"
-	case "Boolean", "True":
-		return "bool"
-	case "Integer or String":
-		return "ChatID"
-	case "InputFile or String":
-		if optional {
-			return "*InputFile"
-		}
-		return "InputFile"
-	default:
-		if strings.HasPrefix(text, "Array of ") || strings.HasPrefix(text, "array of ") {
-			text = strings.TrimPrefix(strings.TrimPrefix(text, "Array of "), "array of ")
-			return "[]" + parseType(text, false)
-		}
+// parseTypeRef resolves a field's Bot API doc type text into a types.Ref,
+// for callers that need more than the Go source string: whether it's a
+// polymorphic Union, what an Array's element type is, and so on.
+func parseTypeRef(text string, optional bool) types.Ref {
+	return types.Parse(removeHTML(text), optional)
+}
 
-		if optional {
-			return "*" + text
-		}
-		return text
+func parseType(text string, optional bool) string {
+	return parseTypeRef(text, optional).Go()
+}
+
+// generateUnionDecl renders the tagged-interface and dispatching-unmarshal
+// declarations for a field's doc type text, if parseTypeRef resolves it to
+// a types.Union. This is the path a struct-codegen pass takes once it
+// needs more than parseType's bare Go source string: a polymorphic field
+// still has to decode into one of several concrete types, so its Ref has
+// to carry that dispatch logic instead of collapsing straight to a name.
+// Returns ok == false for any other Ref.
+func generateUnionDecl(text string, optional bool) (decl string, ok bool) {
+	u, ok := parseTypeRef(text, optional).(types.Union)
+	if !ok {
+		return "", false
 	}
+	return u.GenerateInterface() + "\n\n" + u.GenerateUnmarshal() + "\n", true
 }
 
 func uppercaseWords(text string) string {
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateUnionDecl(t *testing.T) {
+	decl, ok := generateUnionDecl("InlineQueryResult", false)
+	if !ok {
+		t.Fatalf("generateUnionDecl(%q) ok = false, want true", "InlineQueryResult")
+	}
+	if want := "type InlineQueryResult interface {"; !strings.Contains(decl, want) {
+		t.Errorf("generateUnionDecl(...) = %q, want it to contain %q", decl, want)
+	}
+	if want := "func unmarshalInlineQueryResult(discriminator string, data []byte)"; !strings.Contains(decl, want) {
+		t.Errorf("generateUnionDecl(...) = %q, want it to contain %q", decl, want)
+	}
+}
+
+func TestGenerateUnionDeclNonUnion(t *testing.T) {
+	if _, ok := generateUnionDecl("PhotoSize", false); ok {
+		t.Errorf("generateUnionDecl(%q) ok = true, want false", "PhotoSize")
+	}
+}
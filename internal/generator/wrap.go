@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/width"
+)
+
+// maxLineLen is the default wrap width (in display columns) used when a
+// WrapOptions doesn't set MaxWidth, matching the generator's line length
+// for the doc comments it emits.
+const maxLineLen = 80
+
+// WrapOptions configures splitTextToFitLine. MaxWidth is measured in
+// display columns (grapheme-cluster width), not bytes or runes.
+type WrapOptions struct {
+	MaxWidth int
+
+	// Indent/HangingIndent are subtracted from MaxWidth when budgeting the
+	// first line and every following line respectively, so a caller that
+	// prefixes lines itself (e.g. a bullet marker) doesn't need to redo
+	// the width math.
+	Indent        string
+	HangingIndent string
+
+	// BreakLongWords hard-breaks a single token wider than the available
+	// budget into multiple lines, grapheme-cluster by grapheme-cluster.
+	// By default such a token is left to overflow its own line.
+	BreakLongWords bool
+
+	// PreserveURLs exempts http(s) URLs and "--url--" sentinel tokens
+	// from BreakLongWords, since splitting one mid-token makes it unusable.
+	PreserveURLs bool
+}
+
+// WrappedLines is the result of splitTextToFitLine. It can be joined with
+// any per-line delimiter after the fact, so wrapping the same text for two
+// different comment prefixes doesn't require wrapping it twice.
+type WrappedLines []string
+
+// Join renders the wrapped lines with delimiter prefixed to each one.
+func (w WrappedLines) Join(delimiter string) string {
+	if len(w) == 0 {
+		return ""
+	}
+	return delimiter + strings.Join(w, "\n"+delimiter)
+}
+
+// splitTextToFitLine wraps text to opts.MaxWidth display columns. Any
+// number of embedded newlines is supported (each one ends a non-empty
+// line, rather than the old two-segments-only limit; consecutive newlines
+// do not produce empty lines), and words are measured by
+// grapheme-cluster width so CJK characters, accents, and emoji sequences
+// wrap correctly.
+func splitTextToFitLine(text string, opts WrapOptions) WrappedLines {
+	maxWidth := opts.MaxWidth
+	if maxWidth <= 0 {
+		maxWidth = maxLineLen
+	}
+
+	firstBudget := maxWidth - graphemeWidth(opts.Indent)
+	restBudget := maxWidth - graphemeWidth(opts.HangingIndent)
+	if firstBudget <= 0 {
+		firstBudget = 1
+	}
+	if restBudget <= 0 {
+		restBudget = 1
+	}
+
+	var result WrappedLines
+	line := strings.Builder{}
+	lineWidth := 0
+
+	budget := func() int {
+		if len(result) == 0 {
+			return firstBudget
+		}
+		return restBudget
+	}
+	flush := func() {
+		result = append(result, line.String())
+		line.Reset()
+		lineWidth = 0
+	}
+	appendToken := func(token string, tokenWidth int) {
+		if lineWidth > 0 && lineWidth+tokenWidth+1 > budget() {
+			flush()
+		}
+		line.WriteString(token + " ")
+		lineWidth += tokenWidth + 1
+	}
+
+	for _, segment := range strings.Split(text, "\n") {
+		if line.Len() > 0 {
+			flush()
+		}
+
+		for _, token := range strings.Split(segment, " ") {
+			if token == "" {
+				continue
+			}
+			tokenWidth := graphemeWidth(token)
+
+			if opts.BreakLongWords && tokenWidth > budget() && !(opts.PreserveURLs && isURLToken(token)) {
+				for _, piece := range breakLongToken(token, budget()) {
+					appendToken(piece, graphemeWidth(piece))
+				}
+				continue
+			}
+
+			appendToken(token, tokenWidth)
+		}
+	}
+	if line.Len() != 0 {
+		flush()
+	}
+
+	return result
+}
+
+func isURLToken(token string) bool {
+	if strings.HasPrefix(token, "http://") || strings.HasPrefix(token, "https://") {
+		return true
+	}
+	return len(token) > 4 && strings.HasPrefix(token, "--") && strings.HasSuffix(token, "--")
+}
+
+// graphemeWidth measures the display width of s: each grapheme cluster
+// (so ZWJ emoji sequences count once) contributes 2 columns if it starts
+// with an East Asian wide/fullwidth rune, 1 otherwise.
+func graphemeWidth(s string) int {
+	total := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		runes := g.Runes()
+		if len(runes) == 0 {
+			continue
+		}
+		switch width.LookupRune(runes[0]).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			total += 2
+		default:
+			total++
+		}
+	}
+	return total
+}
+
+// breakLongToken splits token into grapheme-cluster chunks that each fit
+// within maxWidth display columns.
+func breakLongToken(token string, maxWidth int) []string {
+	if maxWidth <= 0 {
+		maxWidth = 1
+	}
+
+	var chunks []string
+	chunk := strings.Builder{}
+	chunkWidth := 0
+
+	g := uniseg.NewGraphemes(token)
+	for g.Next() {
+		cluster := g.Str()
+		w := graphemeWidth(cluster)
+		if chunkWidth > 0 && chunkWidth+w > maxWidth {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+			chunkWidth = 0
+		}
+		chunk.WriteString(cluster)
+		chunkWidth += w
+	}
+	if chunk.Len() > 0 {
+		chunks = append(chunks, chunk.String())
+	}
+
+	return chunks
+}
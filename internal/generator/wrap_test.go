@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphemeWidthCJKIsDouble(t *testing.T) {
+	if w := graphemeWidth("你好"); w != 4 {
+		t.Errorf("graphemeWidth(%q) = %d, want 4", "你好", w)
+	}
+	if w := graphemeWidth("hi"); w != 2 {
+		t.Errorf("graphemeWidth(%q) = %d, want 2", "hi", w)
+	}
+}
+
+func TestGraphemeWidthZWJEmojiCountsAsOneCluster(t *testing.T) {
+	// U+1F468 U+200D U+1F469 U+200D U+1F467 is a single "family" grapheme
+	// cluster (man-ZWJ-woman-ZWJ-girl); measuring rune-by-rune would give
+	// 5+ columns, but it must be counted as one cluster.
+	family := "\U0001F468‍\U0001F469‍\U0001F467"
+	if w := graphemeWidth(family); w > 2 {
+		t.Errorf("graphemeWidth(family emoji) = %d, want <= 2 (one grapheme cluster)", w)
+	}
+}
+
+func TestSplitTextToFitLineRespectsMaxWidth(t *testing.T) {
+	lines := splitTextToFitLine("one two three four five", WrapOptions{MaxWidth: 10})
+	for _, line := range lines {
+		if w := graphemeWidth(strings.TrimSpace(line)); w > 10 {
+			t.Errorf("line %q has width %d, want <= 10", line, w)
+		}
+	}
+	if len(lines) < 2 {
+		t.Fatalf("got %d lines, want wrapping to produce more than one", len(lines))
+	}
+}
+
+func TestSplitTextToFitLineEmbeddedNewlines(t *testing.T) {
+	// Previously any word containing more than one "\n" called os.Exit(2).
+	lines := splitTextToFitLine("a\nb\nc\nd", WrapOptions{MaxWidth: 80})
+	if len(lines) != 4 {
+		t.Fatalf("got %v, want 4 lines", lines)
+	}
+	for i, want := range []string{"a", "b", "c", "d"} {
+		if got := strings.TrimSpace(lines[i]); got != want {
+			t.Errorf("lines[%d] = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestSplitTextToFitLinePreservesURLToken(t *testing.T) {
+	longURL := "https://example.com/very/long/path/that/would/otherwise/be/broken"
+	lines := splitTextToFitLine(longURL, WrapOptions{
+		MaxWidth:       20,
+		BreakLongWords: true,
+		PreserveURLs:   true,
+	})
+	if len(lines) != 1 || strings.TrimSpace(lines[0]) != longURL {
+		t.Fatalf("got %v, want the URL kept intact on one line", lines)
+	}
+}
+
+func TestSplitTextToFitLineBreaksLongWordWhenAllowed(t *testing.T) {
+	lines := splitTextToFitLine("supercalifragilisticexpialidocious", WrapOptions{
+		MaxWidth:       10,
+		BreakLongWords: true,
+	})
+	if len(lines) < 2 {
+		t.Fatalf("got %v, want the long word broken across multiple lines", lines)
+	}
+	for _, line := range lines {
+		if w := graphemeWidth(strings.TrimSpace(line)); w > 10 {
+			t.Errorf("line %q has width %d, want <= 10", line, w)
+		}
+	}
+}
+
+func TestFitTextToLineJoinsWithDelimiter(t *testing.T) {
+	got := fitTextToLine("one two three", "// ")
+	want := "// one two three"
+	if strings.TrimRight(got, " ") != want {
+		t.Errorf("fitTextToLine(...) = %q, want %q", got, want)
+	}
+}
+
+func TestWrappedLinesJoinWithDifferentDelimiters(t *testing.T) {
+	lines := splitTextToFitLine("one two", WrapOptions{MaxWidth: 80})
+	if got, want := strings.TrimRight(lines.Join("// "), " "), "// one two"; got != want {
+		t.Errorf("Join(%q) = %q, want %q", "// ", got, want)
+	}
+	if got, want := strings.TrimRight(lines.Join("# "), " "), "# one two"; got != want {
+		t.Errorf("Join(%q) = %q, want %q", "# ", got, want)
+	}
+}